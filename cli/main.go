@@ -3,23 +3,45 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/segmentio/kafka-go"
+
+	"github.com/greenlane/ingestion/codec"
+	"github.com/greenlane/ingestion/ingest/retry"
+	pb "github.com/greenlane/ingestion/proto"
+	"github.com/greenlane/observability/logging"
+	"github.com/greenlane/observability/metrics"
 )
 
 const (
 	kafkaBroker = "localhost:19092"
 	kafkaTopic  = "fleet-events"
+	dlqTopic    = "fleet-events-dlq"
 	groupID     = "live-ops-cli"
+	metricsAddr = ":9092"
+
+	lagPollInterval = 5 * time.Second
 )
 
+var logger = logging.New("live-ops-cli")
+
+var kafkaConsumerLag = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "live_ops_cli_kafka_consumer_lag",
+	Help: "Consumer lag reported by the reader's own Stats(), mirrored from the Kafka client.",
+})
+
+func init() {
+	prometheus.MustRegister(kafkaConsumerLag)
+}
+
 // TelemetryEvent represents an event from the fleet
 type TelemetryEvent struct {
 	CarID     string  `json:"car_id"`
@@ -42,13 +64,21 @@ var (
 )
 
 func main() {
+	dlqMode := flag.Bool("dlq", false, "inspect fleet-events-dlq instead of live telemetry")
+	flag.Parse()
+
 	// Print banner
 	printBanner()
 
+	topic := kafkaTopic
+	if *dlqMode {
+		topic = dlqTopic
+	}
+
 	// Create Kafka reader
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        []string{kafkaBroker},
-		Topic:          kafkaTopic,
+		Topic:          topic,
 		GroupID:        groupID,
 		MinBytes:       1,
 		MaxBytes:       10e6,
@@ -57,10 +87,18 @@ func main() {
 	})
 	defer reader.Close()
 
-	log.Println("✅ Connected to Redpanda")
-	log.Printf("📡 Listening to topic: %s\n", kafkaTopic)
+	metrics.ServeSidecar(metricsAddr, nil)
+	go mirrorKafkaLag(reader)
+
+	logger.Info("Connected to Redpanda")
+	logger.Infof("Listening to topic: %s", topic)
 	fmt.Println()
 
+	if *dlqMode {
+		runDLQInspector(reader)
+		return
+	}
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -90,25 +128,121 @@ func main() {
 				if ctx.Err() != nil {
 					return
 				}
-				log.Printf("Error reading message: %v", err)
+				logger.Errorf("Error reading message: %v", err)
 				continue
 			}
 
 			eventCount++
 
-			// Parse event
-			var event TelemetryEvent
-			if err := json.Unmarshal(message.Value, &event); err != nil {
-				log.Printf("Failed to parse event: %v", err)
+			// Parse event, negotiating JSON vs. Protobuf by sniffing the
+			// Confluent wire-format prefix so both producer generations
+			// can coexist on the topic during migration.
+			event, err := decodeEvent(message.Value)
+			if err != nil {
+				logger.Warnf("Failed to parse event: %v", err)
 				continue
 			}
 
 			// Display event with color coding
-			displayEvent(&event, eventCount)
+			displayEvent(event, eventCount)
+		}
+	}
+}
+
+// Codecs used to negotiate the wire format of an incoming message; decoding
+// doesn't need a schema registry client, so both are used zero-valued.
+var (
+	jsonCodec     codec.JSONCodec
+	protobufCodec codec.ProtobufCodec
+)
+
+// decodeEvent picks JSONCodec or ProtobufCodec based on the Confluent
+// wire-format prefix and returns the record in the CLI's display shape.
+func decodeEvent(data []byte) (*TelemetryEvent, error) {
+	var (
+		carStatus *pb.CarStatus
+		err       error
+	)
+
+	if codec.IsProtobufWire(data) {
+		carStatus, err = protobufCodec.Decode(data)
+	} else {
+		carStatus, err = jsonCodec.Decode(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &TelemetryEvent{
+		CarID:     carStatus.CarId,
+		Latitude:  carStatus.Latitude,
+		Longitude: carStatus.Longitude,
+		Battery:   carStatus.BatteryLevel,
+		Velocity:  carStatus.Velocity,
+		Timestamp: carStatus.Timestamp,
+		EventType: "telemetry",
+	}, nil
+}
+
+// mirrorKafkaLag polls the reader's own Stats() on a fixed interval and
+// republishes the consumer lag as a Prometheus gauge, so dashboards built
+// against the ingestion side's metrics can track the Live Ops CLI's
+// consumer the same way.
+func mirrorKafkaLag(reader *kafka.Reader) {
+	ticker := time.NewTicker(lagPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		stats := reader.Stats()
+		kafkaConsumerLag.Set(float64(stats.Lag))
+	}
+}
+
+// runDLQInspector consumes fleet-events-dlq and prints each dead-lettered
+// record with the failure reason, attempt count, and the time it was
+// first seen, colorized the same way live telemetry is.
+func runDLQInspector(reader *kafka.Reader) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		fmt.Println("\n\n🛑 Shutting down...")
+		cancel()
+	}()
+
+	for {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Errorf("Error reading DLQ message: %v", err)
+			continue
 		}
+
+		var record retry.Record
+		if err := json.Unmarshal(message.Value, &record); err != nil {
+			logger.Warnf("Failed to parse DLQ record: %v", err)
+			continue
+		}
+
+		displayDLQRecord(&record)
 	}
 }
 
+func displayDLQRecord(record *retry.Record) {
+	firstSeen := time.UnixMilli(record.FirstSeen).Format("15:04:05")
+
+	fmt.Printf("[%s] ", timestampColor.Sprint(firstSeen))
+	fmt.Printf("%-10s | ", carIDColor.Sprint(record.CarStatus.CarId))
+	fmt.Printf("sink: %s | attempts: %d | ", record.Sink, record.Attempts)
+	criticalColor.Printf("reason: %s", record.Reason)
+	fmt.Println()
+}
+
 func printBanner() {
 	banner := `
 ╔═══════════════════════════════════════════════════════════════╗
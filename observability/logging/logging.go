@@ -0,0 +1,28 @@
+// Package logging configures the structured logger shared by all three
+// GreenLane services, replacing ad-hoc log.Printf calls.
+package logging
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// New returns a logrus entry tagged with service, emitting JSON in
+// production (GREENLANE_ENV=production) and colorized console output
+// everywhere else.
+func New(service string) *logrus.Entry {
+	logger := logrus.New()
+	logger.SetOutput(os.Stdout)
+
+	if os.Getenv("GREENLANE_ENV") == "production" {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{
+			ForceColors:   true,
+			FullTimestamp: true,
+		})
+	}
+
+	return logger.WithField("service", service)
+}
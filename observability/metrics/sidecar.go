@@ -0,0 +1,55 @@
+// Package metrics stands up the Prometheus /metrics + pprof + /vars
+// side-car HTTP server shared by all three GreenLane services.
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// BuildSHA is overridden at build time via -ldflags
+// "-X github.com/greenlane/observability/metrics.BuildSHA=<sha>".
+var BuildSHA = "dev"
+
+var startTime = time.Now()
+
+// ConnectionCounter reports the number of live connections for the /vars
+// endpoint; services pass a closure over whatever they track (gRPC
+// streams, Kafka readers, etc).
+type ConnectionCounter func() int
+
+// ServeSidecar starts (in a goroutine) an HTTP server on addr exposing:
+//   - /metrics: Prometheus exposition format
+//   - /debug/pprof/*: Go's standard profiler endpoints
+//   - /vars: build SHA, start time, and live connection count
+func ServeSidecar(addr string, liveConnections ConnectionCounter) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("/vars", func(w http.ResponseWriter, r *http.Request) {
+		connections := 0
+		if liveConnections != nil {
+			connections = liveConnections()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"build_sha":        BuildSHA,
+			"start_time":       startTime.Format(time.RFC3339),
+			"uptime_seconds":   time.Since(startTime).Seconds(),
+			"live_connections": connections,
+		})
+	})
+
+	go http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,28 @@
+// Package auth replaces the ingestion service's single hard-coded API
+// token with pluggable per-tenant authentication: mutual TLS (tenant ID
+// from the client cert CN) or a JWT bearer token (tenant ID from a claim).
+package auth
+
+import "context"
+
+// Authenticator resolves the tenant ID for an incoming gRPC call, or
+// returns an error (already a gRPC status error) if the call shouldn't be
+// allowed.
+type Authenticator interface {
+	Authenticate(ctx context.Context) (tenantID string, err error)
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context carrying tenantID, for the interceptor to
+// attach and downstream handlers to read back with TenantFromContext.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID attached by the auth
+// interceptor, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok
+}
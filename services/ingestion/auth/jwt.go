@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// JWTAuthenticator verifies RS256-signed bearer tokens and extracts the
+// tenant_id claim. It only understands RS256: it's meant to pair with a
+// central auth service that holds the signing key.
+type JWTAuthenticator struct {
+	PublicKey *rsa.PublicKey
+	Issuer    string
+	Audience  string
+}
+
+type jwtClaims struct {
+	Issuer   string `json:"iss"`
+	Audience string `json:"aud"`
+	Expiry   int64  `json:"exp"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (a JWTAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	tokens := md.Get("authorization")
+	if len(tokens) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+	tokenString := strings.TrimPrefix(tokens[0], "Bearer ")
+
+	claims, err := a.verify(tokenString)
+	if err != nil {
+		return "", status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	if claims.Issuer != a.Issuer {
+		return "", status.Error(codes.Unauthenticated, "unexpected issuer")
+	}
+	if claims.Audience != a.Audience {
+		return "", status.Error(codes.Unauthenticated, "unexpected audience")
+	}
+	if time.Now().Unix() > claims.Expiry {
+		return "", status.Error(codes.Unauthenticated, "token expired")
+	}
+	if claims.TenantID == "" {
+		return "", status.Error(codes.Unauthenticated, "token missing tenant_id claim")
+	}
+
+	return claims.TenantID, nil
+}
+
+// verify checks the RS256 signature over header.payload and decodes the
+// claims; it does not itself check iss/aud/exp.
+func (a JWTAuthenticator) verify(tokenString string) (*jwtClaims, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid header encoding: %w", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(a.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		return nil, fmt.Errorf("signature verification failed: %w", err)
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+
+	var claims jwtClaims
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %w", err)
+	}
+
+	return &claims, nil
+}
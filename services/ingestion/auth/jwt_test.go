@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func generateTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+// signToken hand-rolls a compact JWT (base64url header.payload.signature)
+// the same way JWTAuthenticator.verify expects to parse one, so these
+// tests don't need a JWT library either.
+func signToken(t *testing.T, key *rsa.PrivateKey, alg string, claims jwtClaims) string {
+	t.Helper()
+
+	headerJSON, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		t.Fatalf("failed to marshal header: %v", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("failed to marshal claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func contextWithToken(token string) context.Context {
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	key := generateTestKey(t)
+	otherKey := generateTestKey(t)
+
+	authn := JWTAuthenticator{
+		PublicKey: &key.PublicKey,
+		Issuer:    "greenlane-auth",
+		Audience:  "greenlane-ingestion",
+	}
+
+	baseClaims := jwtClaims{
+		Issuer:   authn.Issuer,
+		Audience: authn.Audience,
+		Expiry:   time.Now().Add(time.Hour).Unix(),
+		TenantID: "tenant-a",
+	}
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantErr    bool
+		wantTenant string
+	}{
+		{
+			name:       "valid token",
+			ctx:        contextWithToken(signToken(t, key, "RS256", baseClaims)),
+			wantTenant: "tenant-a",
+		},
+		{
+			name:    "missing metadata",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+		{
+			name:    "signed with the wrong key",
+			ctx:     contextWithToken(signToken(t, otherKey, "RS256", baseClaims)),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			ctx:     contextWithToken(signToken(t, key, "HS256", baseClaims)),
+			wantErr: true,
+		},
+		{
+			name: "expired token",
+			ctx: contextWithToken(signToken(t, key, "RS256", jwtClaims{
+				Issuer: authn.Issuer, Audience: authn.Audience,
+				Expiry: time.Now().Add(-time.Hour).Unix(), TenantID: "tenant-a",
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			ctx: contextWithToken(signToken(t, key, "RS256", jwtClaims{
+				Issuer: "someone-else", Audience: authn.Audience,
+				Expiry: time.Now().Add(time.Hour).Unix(), TenantID: "tenant-a",
+			})),
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			ctx: contextWithToken(signToken(t, key, "RS256", jwtClaims{
+				Issuer: authn.Issuer, Audience: "someone-else",
+				Expiry: time.Now().Add(time.Hour).Unix(), TenantID: "tenant-a",
+			})),
+			wantErr: true,
+		},
+		{
+			name: "missing tenant_id claim",
+			ctx: contextWithToken(signToken(t, key, "RS256", jwtClaims{
+				Issuer: authn.Issuer, Audience: authn.Audience,
+				Expiry: time.Now().Add(time.Hour).Unix(),
+			})),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID, err := authn.Authenticate(tt.ctx)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Authenticate() err = nil, want an error (tenant %q)", tenantID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() unexpected error: %v", err)
+			}
+			if tenantID != tt.wantTenant {
+				t.Fatalf("Authenticate() tenantID = %q, want %q", tenantID, tt.wantTenant)
+			}
+		})
+	}
+}
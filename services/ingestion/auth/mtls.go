@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MTLSAuthenticator trusts the gRPC transport's mutual-TLS handshake: it
+// requires grpc.Creds(credentials.NewTLS(...)) to be configured with
+// client cert verification on the server, and treats the client
+// certificate's Common Name as the tenant ID.
+type MTLSAuthenticator struct{}
+
+func (MTLSAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing peer info")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "connection is not using mTLS")
+	}
+
+	if len(tlsInfo.State.PeerCertificates) == 0 {
+		return "", status.Error(codes.Unauthenticated, "no client certificate presented")
+	}
+
+	return tlsInfo.State.PeerCertificates[0].Subject.CommonName, nil
+}
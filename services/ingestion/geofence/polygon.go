@@ -0,0 +1,71 @@
+// Package geofence maintains each tenant's active geofence polygons and
+// tracks which cars are inside which polygon, so WatchGeofence can emit
+// Enter/Exit events as telemetry updates cross a boundary.
+package geofence
+
+// Point is a latitude/longitude pair.
+type Point struct {
+	Lat, Lon float64
+}
+
+// Polygon is a closed ring of points plus its bounding box, precomputed
+// once so Contains can cheaply reject points that are nowhere close
+// before running the full ray-cast.
+type Polygon struct {
+	ID     string
+	points []Point
+
+	minLat, maxLat float64
+	minLon, maxLon float64
+}
+
+// NewPolygon builds a Polygon from a ring of at least 3 points.
+func NewPolygon(id string, points []Point) Polygon {
+	p := Polygon{ID: id, points: points}
+	p.minLat, p.maxLat = points[0].Lat, points[0].Lat
+	p.minLon, p.maxLon = points[0].Lon, points[0].Lon
+
+	for _, pt := range points[1:] {
+		p.minLat = min(p.minLat, pt.Lat)
+		p.maxLat = max(p.maxLat, pt.Lat)
+		p.minLon = min(p.minLon, pt.Lon)
+		p.maxLon = max(p.maxLon, pt.Lon)
+	}
+	return p
+}
+
+// Contains reports whether (lat, lon) is inside the polygon.
+func (p Polygon) Contains(lat, lon float64) bool {
+	if lat < p.minLat || lat > p.maxLat || lon < p.minLon || lon > p.maxLon {
+		return false
+	}
+	return rayCast(p.points, lat, lon)
+}
+
+// rayCast is the standard even-odd point-in-polygon test: count crossings
+// of a ray cast east from (lat, lon) through the polygon's edges.
+func rayCast(points []Point, lat, lon float64) bool {
+	inside := false
+	for i, j := 0, len(points)-1; i < len(points); j, i = i, i+1 {
+		pi, pj := points[i], points[j]
+		if (pi.Lat > lat) != (pj.Lat > lat) &&
+			lon < (pj.Lon-pi.Lon)*(lat-pi.Lat)/(pj.Lat-pi.Lat)+pi.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
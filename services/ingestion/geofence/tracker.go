@@ -0,0 +1,173 @@
+package geofence
+
+import (
+	"strings"
+	"sync"
+)
+
+// EventType distinguishes a car crossing into a geofence from crossing
+// back out of one.
+type EventType int
+
+const (
+	Enter EventType = iota + 1
+	Exit
+)
+
+// Event is published whenever Check sees a car's containment state
+// change for some polygon.
+type Event struct {
+	TenantID   string
+	GeofenceID string
+	CarID      string
+	Type       EventType
+}
+
+// subscriberBuffer bounds how many pending events a slow WatchGeofence
+// stream can fall behind by before Check starts dropping events for it,
+// so the telemetry hot path never blocks on a stalled subscriber.
+const subscriberBuffer = 16
+
+// Tracker holds the active per-tenant polygon Index plus each car's
+// last-known containment state, so Check can tell "just entered" apart
+// from "was already inside."
+type Tracker struct {
+	index *Index
+
+	mu     sync.Mutex
+	inside map[string]map[string]bool // tenant -> "geofenceID:carID" -> currently inside
+
+	subMu sync.Mutex
+	subs  map[string]map[string][]chan Event // tenant -> geofenceID -> subscriber channels
+}
+
+// NewTracker returns an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		index:  NewIndex(),
+		inside: make(map[string]map[string]bool),
+		subs:   make(map[string]map[string][]chan Event),
+	}
+}
+
+// Watch registers polygon as active for tenantID and returns a channel of
+// Enter/Exit events for that polygon. The caller must invoke cancel when
+// it stops watching (e.g. the WatchGeofence stream ends) to deregister
+// the polygon, release the channel, and forget the polygon's containment
+// state.
+func (t *Tracker) Watch(tenantID string, polygon Polygon) (events <-chan Event, cancel func()) {
+	t.index.Upsert(tenantID, polygon)
+
+	ch := make(chan Event, subscriberBuffer)
+	t.subMu.Lock()
+	tenantSubs := t.subs[tenantID]
+	if tenantSubs == nil {
+		tenantSubs = make(map[string][]chan Event)
+		t.subs[tenantID] = tenantSubs
+	}
+	tenantSubs[polygon.ID] = append(tenantSubs[polygon.ID], ch)
+	t.subMu.Unlock()
+
+	cancel = func() {
+		t.index.Remove(tenantID, polygon.ID)
+
+		t.subMu.Lock()
+		if tenantSubs := t.subs[tenantID]; tenantSubs != nil {
+			chans := tenantSubs[polygon.ID]
+			for i, c := range chans {
+				if c == ch {
+					chans = append(chans[:i], chans[i+1:]...)
+					break
+				}
+			}
+			if len(chans) == 0 {
+				delete(tenantSubs, polygon.ID)
+			} else {
+				tenantSubs[polygon.ID] = chans
+			}
+			if len(tenantSubs) == 0 {
+				delete(t.subs, tenantID)
+			}
+		}
+		t.subMu.Unlock()
+		close(ch)
+
+		t.mu.Lock()
+		if tenantState := t.inside[tenantID]; tenantState != nil {
+			prefix := polygon.ID + ":"
+			for key := range tenantState {
+				if strings.HasPrefix(key, prefix) {
+					delete(tenantState, key)
+				}
+			}
+			if len(tenantState) == 0 {
+				delete(t.inside, tenantID)
+			}
+		}
+		t.mu.Unlock()
+	}
+	return ch, cancel
+}
+
+// Check runs (lat, lon) for carID against every active polygon for
+// tenantID via ray-casting, and publishes an Enter or Exit event for any
+// containment change. It's called inline in the telemetry hot path,
+// before the Redis write, so alerts fire with sub-ms overhead.
+func (t *Tracker) Check(tenantID, carID string, lat, lon float64) {
+	polygons := t.index.ByTenant(tenantID)
+	if len(polygons) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	tenantState := t.inside[tenantID]
+	if tenantState == nil {
+		tenantState = make(map[string]bool)
+		t.inside[tenantID] = tenantState
+	}
+
+	var events []Event
+	for _, polygon := range polygons {
+		stateKey := polygon.ID + ":" + carID
+		wasInside := tenantState[stateKey]
+		nowInside := polygon.Contains(lat, lon)
+
+		if nowInside == wasInside {
+			continue
+		}
+		tenantState[stateKey] = nowInside
+
+		eventType := Exit
+		if nowInside {
+			eventType = Enter
+		}
+		events = append(events, Event{TenantID: tenantID, GeofenceID: polygon.ID, CarID: carID, Type: eventType})
+	}
+	t.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	// Each event only goes to subscribers watching that specific
+	// geofence, so two concurrent WatchGeofence calls for the same
+	// tenant never see each other's polygons.
+	t.subMu.Lock()
+	bygeofence := make(map[string][]chan Event, len(events))
+	for _, event := range events {
+		if _, ok := bygeofence[event.GeofenceID]; ok {
+			continue
+		}
+		bygeofence[event.GeofenceID] = append([]chan Event(nil), t.subs[tenantID][event.GeofenceID]...)
+	}
+	t.subMu.Unlock()
+
+	for _, event := range events {
+		for _, sub := range bygeofence[event.GeofenceID] {
+			select {
+			case sub <- event:
+			default: // slow subscriber: drop rather than block the hot path
+			}
+		}
+	}
+}
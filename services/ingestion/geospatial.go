@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenlane/ingestion/auth"
+	"github.com/greenlane/ingestion/geo"
+	"github.com/greenlane/ingestion/geofence"
+	pb "github.com/greenlane/ingestion/proto"
+)
+
+// defaultNearestCarLimit caps FindNearestCars results when the caller
+// doesn't set max_n.
+const defaultNearestCarLimit = 20
+
+// FindNearestCars ranks cars in the caller's fleet:<tenant>:locations GEO
+// index by distance from (lat, lon), enriching each hit with its
+// last-known battery/velocity from the car:<tenant>:<id> hash and an ETA
+// derived from that velocity.
+func (s *IngestionServer) FindNearestCars(ctx context.Context, req *pb.FindNearestCarsRequest) (*pb.FindNearestCarsResponse, error) {
+	tenantID, ok := auth.TenantFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing tenant in context")
+	}
+
+	maxN := int(req.MaxN)
+	if maxN <= 0 {
+		maxN = defaultNearestCarLimit
+	}
+
+	key := fmt.Sprintf("fleet:%s:locations", tenantID)
+	hits, err := s.redisClient.GeoRadius(ctx, key, req.Lon, req.Lat, &redis.GeoRadiusQuery{
+		Radius:    req.RadiusKm,
+		Unit:      "km",
+		WithCoord: true,
+		WithDist:  true,
+		Sort:      "ASC",
+		Count:     maxN,
+	}).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "GEORADIUS failed: %v", err)
+	}
+
+	cars := make([]*pb.NearestCar, 0, len(hits))
+	for _, hit := range hits {
+		meta, err := s.redisClient.HGetAll(ctx, fmt.Sprintf("car:%s:%s", tenantID, hit.Name)).Result()
+		if err != nil {
+			continue
+		}
+		battery, _ := strconv.ParseFloat(meta["battery"], 64)
+		velocity, _ := strconv.ParseFloat(meta["velocity"], 64)
+
+		if req.MinBattery > 0 && battery < req.MinBattery {
+			continue
+		}
+
+		cars = append(cars, &pb.NearestCar{
+			CarId:        hit.Name,
+			Latitude:     hit.Latitude,
+			Longitude:    hit.Longitude,
+			DistanceKm:   hit.Dist,
+			EtaSeconds:   geo.ETASeconds(hit.Dist, velocity),
+			BatteryLevel: battery,
+		})
+	}
+
+	return &pb.FindNearestCarsResponse{Cars: cars}, nil
+}
+
+// reserveCarTTL bounds how long a reservation holds a car before it's
+// released automatically, in case the caller never follows through.
+const reserveCarTTL = 5 * time.Minute
+
+// reserveScript does an atomic SETNX-with-TTL: plain SETNX followed by a
+// separate PEXPIRE would leave a window where a crash could strand the
+// key without an expiry, so both run inside one Lua script.
+var reserveScript = redis.NewScript(`
+if redis.call('SETNX', KEYS[1], ARGV[1]) == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// ReserveCar atomically holds car_id for the caller's tenant, failing if
+// another caller already holds it.
+func (s *IngestionServer) ReserveCar(ctx context.Context, req *pb.ReserveCarRequest) (*pb.ReserveCarResponse, error) {
+	tenantID, ok := auth.TenantFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing tenant in context")
+	}
+	if req.CarId == "" {
+		return nil, status.Error(codes.InvalidArgument, "car_id is required")
+	}
+
+	key := fmt.Sprintf("reservation:%s:%s", tenantID, req.CarId)
+	result, err := reserveScript.Run(ctx, s.redisClient, []string{key}, tenantID, reserveCarTTL.Milliseconds()).Int()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "reservation failed: %v", err)
+	}
+
+	if result == 0 {
+		return &pb.ReserveCarResponse{Reserved: false, Message: "car already reserved"}, nil
+	}
+	return &pb.ReserveCarResponse{Reserved: true, Message: "reserved"}, nil
+}
+
+// WatchGeofence registers req's polygon as active for the caller's tenant
+// and streams Enter/Exit events as telemetry crosses its boundary, until
+// the caller cancels the stream.
+func (s *IngestionServer) WatchGeofence(req *pb.WatchGeofenceRequest, stream pb.FleetService_WatchGeofenceServer) error {
+	tenantID, ok := auth.TenantFromContext(stream.Context())
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing tenant in context")
+	}
+	if len(req.Polygon) < 3 {
+		return status.Error(codes.InvalidArgument, "polygon must have at least 3 points")
+	}
+
+	points := make([]geofence.Point, len(req.Polygon))
+	for i, p := range req.Polygon {
+		points[i] = geofence.Point{Lat: p.Latitude, Lon: p.Longitude}
+	}
+
+	events, cancel := s.geofences.Watch(tenantID, geofence.NewPolygon(req.GeofenceId, points))
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event := <-events:
+			if err := stream.Send(geofenceEventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func geofenceEventToProto(event geofence.Event) *pb.GeofenceEvent {
+	eventType := pb.GeofenceEventType_GEOFENCE_EVENT_UNKNOWN
+	switch event.Type {
+	case geofence.Enter:
+		eventType = pb.GeofenceEventType_GEOFENCE_EVENT_ENTER
+	case geofence.Exit:
+		eventType = pb.GeofenceEventType_GEOFENCE_EVENT_EXIT
+	}
+
+	return &pb.GeofenceEvent{
+		GeofenceId: event.GeofenceID,
+		CarId:      event.CarID,
+		EventType:  eventType,
+		Timestamp:  time.Now().UnixMilli(),
+	}
+}
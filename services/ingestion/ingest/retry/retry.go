@@ -0,0 +1,172 @@
+// Package retry wraps a telemetry sink (Redis, Kafka) with a bounded,
+// in-memory retry queue so a backend outage doesn't silently drop
+// telemetry. Records that keep failing past maxAttempts are published to
+// the fleet-events-dlq topic instead of being dropped.
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+
+	pb "github.com/greenlane/ingestion/proto"
+)
+
+const (
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+	maxAttempts    = 5
+	defaultCap     = 1000
+
+	// maxConcurrentRetries bounds how many items can be retrying (and
+	// therefore sleeping in backoff) at once, so a sustained backend
+	// outage fans out a fixed worker pool instead of one goroutine per
+	// queued item.
+	maxConcurrentRetries = 32
+)
+
+// Sink is the subset of IngestionServer's write paths the retry queue can
+// drive: Redis and Kafka both satisfy this with a thin adapter.
+type Sink interface {
+	Write(ctx context.Context, tenantID string, carStatus *pb.CarStatus) error
+	Name() string
+}
+
+// Record is what lands on fleet-events-dlq once a CarStatus has exhausted
+// its retries against a given sink.
+type Record struct {
+	CarStatus *pb.CarStatus `json:"car_status"`
+	TenantID  string        `json:"tenant_id"`
+	Sink      string        `json:"sink"`
+	Reason    string        `json:"reason"`
+	Attempts  int           `json:"attempts"`
+	FirstSeen int64         `json:"first_seen"`
+}
+
+type pendingItem struct {
+	carStatus *pb.CarStatus
+	tenantID  string
+	reason    string
+	attempts  int
+	firstSeen int64
+}
+
+// Queue retries failed writes to a single Sink with exponential backoff
+// and jitter, then falls back to publishing a Record on dlqWriter.
+type Queue struct {
+	sink      Sink
+	dlqWriter *kafka.Writer
+	items     chan *pendingItem
+	logger    *logrus.Entry
+}
+
+// NewQueue starts a Queue with a bounded backlog of capacity; Enqueue
+// drops (and logs) new failures once the backlog is full rather than
+// blocking the telemetry hot path. The backlog is drained by a fixed pool
+// of maxConcurrentRetries workers, so the number of items concurrently
+// retrying (and sleeping in backoff) is bounded too, not just the backlog.
+// logger is the service's shared structured logger (see
+// github.com/greenlane/observability/logging).
+func NewQueue(sink Sink, dlqWriter *kafka.Writer, capacity int, logger *logrus.Entry) *Queue {
+	if capacity <= 0 {
+		capacity = defaultCap
+	}
+
+	q := &Queue{
+		sink:      sink,
+		dlqWriter: dlqWriter,
+		items:     make(chan *pendingItem, capacity),
+		logger:    logger.WithField("sink", sink.Name()),
+	}
+	for i := 0; i < maxConcurrentRetries; i++ {
+		go q.run()
+	}
+	return q
+}
+
+// Enqueue schedules carStatus for retry against the queue's sink. reason
+// is the error that triggered the first attempt.
+func (q *Queue) Enqueue(carStatus *pb.CarStatus, tenantID, reason string) {
+	item := &pendingItem{
+		carStatus: carStatus,
+		tenantID:  tenantID,
+		reason:    reason,
+		firstSeen: time.Now().UnixMilli(),
+	}
+
+	select {
+	case q.items <- item:
+	default:
+		q.logger.Warnf("retry queue is full, dropping record for car %s", carStatus.CarId)
+	}
+}
+
+// run is one of the fixed pool of worker goroutines started by NewQueue;
+// it drains items directly rather than spawning a goroutine per item.
+func (q *Queue) run() {
+	for item := range q.items {
+		q.drain(item)
+	}
+}
+
+// drain retries item against the sink with exponential backoff until it
+// succeeds or exhausts maxAttempts, at which point it is handed to the DLQ.
+func (q *Queue) drain(item *pendingItem) {
+	ctx := context.Background()
+	backoff := initialBackoff
+
+	for {
+		item.attempts++
+		err := q.sink.Write(ctx, item.tenantID, item.carStatus)
+		if err == nil {
+			return
+		}
+		item.reason = err.Error()
+
+		if item.attempts >= maxAttempts {
+			if err := q.publishToDLQ(ctx, item); err != nil {
+				q.logger.Errorf("failed to publish to DLQ after exhausting retries: %v", err)
+			}
+			return
+		}
+
+		time.Sleep(withJitter(backoff))
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (q *Queue) publishToDLQ(ctx context.Context, item *pendingItem) error {
+	record := Record{
+		CarStatus: item.carStatus,
+		TenantID:  item.tenantID,
+		Sink:      q.sink.Name(),
+		Reason:    item.reason,
+		Attempts:  item.attempts,
+		FirstSeen: item.firstSeen,
+	}
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return q.dlqWriter.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(item.carStatus.CarId),
+		Value: value,
+		Time:  time.Now(),
+	})
+}
+
+// withJitter returns a duration in [d/2, d], so retrying goroutines across
+// many cars don't all wake up and hammer the sink at once.
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
-	"log"
 	"net"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -14,24 +18,59 @@ import (
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 
+	"github.com/greenlane/ingestion/auth"
+	"github.com/greenlane/ingestion/codec"
+	"github.com/greenlane/ingestion/geofence"
+	"github.com/greenlane/ingestion/ingest/retry"
+	"github.com/greenlane/ingestion/metrics"
 	pb "github.com/greenlane/ingestion/proto"
+	"github.com/greenlane/ingestion/ratelimit"
+	"github.com/greenlane/ingestion/registry"
+	"github.com/greenlane/observability/logging"
+	obsmetrics "github.com/greenlane/observability/metrics"
 )
 
+var logger = logging.New("ingestion")
+
 const (
-	grpcPort      = ":50051"
-	redisAddr     = "localhost:6379"
-	kafkaBroker   = "localhost:19092"
-	kafkaTopic    = "fleet-events"
-	apiTokenValue = "greenlane-secret-token"
+	grpcPort               = ":50051"
+	redisAddr              = "localhost:6379"
+	kafkaBroker            = "localhost:19092"
+	kafkaTopic             = "fleet-events"
+	dlqTopic               = "fleet-events-dlq"
+	schemaRegistryURL      = "http://localhost:8085"
+	telemetrySchemaSubject = "fleet-events-value"
+	retryQueueCapacity     = 1000
+
+	tlsCertFile     = "certs/server.crt"
+	tlsKeyFile      = "certs/server.key"
+	tlsClientCAFile = "certs/client-ca.crt"
+
+	jwtIssuer        = "greenlane-auth"
+	jwtAudience      = "greenlane-ingestion"
+	jwtPublicKeyFile = "certs/jwt-public.pem"
+
+	rateLimitPerSec = 50.0
+	rateLimitBurst  = 100.0
+
+	metricsAddr = ":9090"
 )
 
 type IngestionServer struct {
 	pb.UnimplementedFleetServiceServer
 	redisClient *redis.Client
 	kafkaWriter *kafka.Writer
+	dlqWriter   *kafka.Writer
+	codec       codec.TelemetryCodec
+	redisRetry  *retry.Queue
+	kafkaRetry  *retry.Queue
+	authn       auth.Authenticator
+	limiter     *ratelimit.Limiter
+	liveStreams atomic.Int64
+	geofences   *geofence.Tracker
 }
 
 func main() {
@@ -45,9 +84,9 @@ func main() {
 	// Test Redis connection
 	ctx := context.Background()
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Fatalf("ERROR: Failed to connect with Redis: %v", err)
+		logger.Fatalf("Failed to connect with Redis: %v", err)
 	}
-	log.Println("INFO: Connected to Redis")
+	logger.Info("Connected to Redis")
 
 	// Initialize Kafka writer
 	kafkaWriter := &kafka.Writer{
@@ -61,37 +100,64 @@ func main() {
 	// Test Kafka connection by creating topic
 	conn, err := kafka.Dial("tcp", kafkaBroker)
 	if err != nil {
-		log.Fatalf("ERROR: Failed to connect to Kafka: %v", err)
+		logger.Fatalf("Failed to connect to Kafka: %v", err)
 	}
 	defer conn.Close()
-	log.Println("SUCCESS: Connected to Redpanda (Kafka)")
+	logger.Info("Connected to Redpanda (Kafka)")
+
+	// Register the CarStatus schema and get a Protobuf codec wired to it.
+	// Falls back to JSON so the service still starts if the registry is
+	// unreachable (e.g. during local dev without the full stack up).
+	telemetryCodec := newTelemetryCodec()
 
-	// Create gRPC server with auth interceptor
-	server := grpc.NewServer(
-		grpc.UnaryInterceptor(authUnaryInterceptor),
-		grpc.StreamInterceptor(authStreamInterceptor),
-	)
+	dlqWriter := &kafka.Writer{
+		Addr:                   kafka.TCP(kafkaBroker),
+		Topic:                  dlqTopic,
+		Balancer:               &kafka.LeastBytes{},
+		AllowAutoTopicCreation: true,
+	}
 
 	ingestionServer := &IngestionServer{
 		redisClient: redisClient,
 		kafkaWriter: kafkaWriter,
+		dlqWriter:   dlqWriter,
+		codec:       telemetryCodec,
+		authn:       newAuthenticator(),
+		limiter:     ratelimit.NewLimiter(rateLimitPerSec, rateLimitBurst),
+		geofences:   geofence.NewTracker(),
+	}
+	ingestionServer.redisRetry = retry.NewQueue(redisSink{server: ingestionServer}, dlqWriter, retryQueueCapacity, logger)
+	ingestionServer.kafkaRetry = retry.NewQueue(kafkaSink{server: ingestionServer}, dlqWriter, retryQueueCapacity, logger)
+
+	// Create gRPC server with auth + rate-limit + metrics interceptors,
+	// over mTLS when server certs are configured.
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(metrics.UnaryServerInterceptor, ingestionServer.authUnaryInterceptor),
+		grpc.ChainStreamInterceptor(metrics.StreamServerInterceptor, ingestionServer.authStreamInterceptor),
+	}
+	if tlsConfig := loadServerTLSConfig(); tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
 	}
+	server := grpc.NewServer(serverOpts...)
 
 	pb.RegisterFleetServiceServer(server, ingestionServer)
 
+	// Prometheus /metrics, pprof, and /vars side-car.
+	obsmetrics.ServeSidecar(metricsAddr, func() int { return int(ingestionServer.liveStreams.Load()) })
+
 	// Start listening
 	listener, err := net.Listen("tcp", grpcPort)
 	if err != nil {
-		log.Fatalf("ERROR: Failed to listen: %v", err)
+		logger.Fatalf("Failed to listen: %v", err)
 	}
 
-	log.Printf("SUCCESS: GreenLane Ingestion Service started on %s", grpcPort)
-	log.Println("INFO: Waiting for EV telemetry streams...")
+	logger.Infof("GreenLane Ingestion Service started on %s", grpcPort)
+	logger.Info("Waiting for EV telemetry streams...")
 
 	// Graceful shutdown
 	go func() {
 		if err := server.Serve(listener); err != nil {
-			log.Fatalf("ERROR: Server failed: %v", err)
+			logger.Fatalf("Server failed: %v", err)
 		}
 	}()
 
@@ -100,37 +166,56 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
-	log.Println("INFO: Shutting down gracefully...")
+	logger.Info("Shutting down gracefully...")
 	server.GracefulStop()
 	redisClient.Close()
 	kafkaWriter.Close()
+	dlqWriter.Close()
 }
 
 // StreamTelemetry handles bidirectional streaming of car telemetry
 func (s *IngestionServer) StreamTelemetry(stream pb.FleetService_StreamTelemetryServer) error {
-	log.Println("INFO: New telemetry stream connected")
+	logger.Info("New telemetry stream connected")
+
+	tenantID, _ := auth.TenantFromContext(stream.Context())
+
+	s.liveStreams.Add(1)
+	defer s.liveStreams.Add(-1)
 
 	for {
 		carStatus, err := stream.Recv()
 		if err != nil {
-			log.Printf("ERROR: Stream ended: %v", err)
+			logger.Errorf("Stream ended: %v", err)
 			return err
 		}
 
-		log.Printf("📍 Received telemetry from Car %s: Lat=%.4f, Lon=%.4f, Battery=%.1f%%",
-			carStatus.CarId, carStatus.Latitude, carStatus.Longitude, carStatus.BatteryLevel)
-
-		// Write to Redis (Geospatial)
-		ctx := context.Background()
-		if err := s.writeToRedis(ctx, carStatus); err != nil {
-			log.Printf("⚠️  Redis write failed: %v", err)
+		done := metrics.TrackInFlight(tenantID)
+		metrics.RecordTenantEvent(tenantID)
+
+		logger.Infof("Received telemetry from Car %s (tenant %s): Lat=%.4f, Lon=%.4f, Battery=%.1f%%",
+			carStatus.CarId, tenantID, carStatus.Latitude, carStatus.Longitude, carStatus.BatteryLevel)
+
+		// Write to Redis (Geospatial). On failure, hand off to the retry
+		// queue instead of dropping the record: it'll be retried with
+		// backoff and, failing that, land on the DLQ topic.
+		ctx := stream.Context()
+		redisErr := s.writeToRedis(ctx, tenantID, carStatus)
+		metrics.RecordRedisWrite(redisErr)
+		if redisErr != nil {
+			logger.Warnf("Redis write failed, queued for retry: %v", redisErr)
+			s.redisRetry.Enqueue(carStatus, tenantID, redisErr.Error())
 		}
 
-		// Emit to Kafka/Redpanda
-		if err := s.emitToKafka(ctx, carStatus); err != nil {
-			log.Printf("⚠️  Kafka emit failed: %v", err)
+		// Emit to Kafka/Redpanda, same retry-then-DLQ treatment.
+		kafkaErr := s.emitToKafka(ctx, tenantID, carStatus)
+		metrics.RecordKafkaWrite(kafkaErr)
+		if kafkaErr != nil {
+			logger.Warnf("Kafka emit failed, queued for retry: %v", kafkaErr)
+			s.kafkaRetry.Enqueue(carStatus, tenantID, kafkaErr.Error())
 		}
 
+		done()
+
 		// Send acknowledgment (optional response)
 		response := &pb.BookingResponse{
 			BookingId: fmt.Sprintf("ack-%s-%d", carStatus.CarId, time.Now().UnixMilli()),
@@ -141,15 +226,20 @@ func (s *IngestionServer) StreamTelemetry(stream pb.FleetService_StreamTelemetry
 		}
 
 		if err := stream.Send(response); err != nil {
-			log.Printf("ERROR: Failed to send response: %v", err)
+			logger.Errorf("Failed to send response: %v", err)
 			return err
 		}
 	}
 }
 
-// writeToRedis stores car location in Redis Geospatial index
-func (s *IngestionServer) writeToRedis(ctx context.Context, carStatus *pb.CarStatus) error {
-	key := "fleet:locations"
+// writeToRedis stores car location in Redis Geospatial index, partitioned
+// per tenant so one fleet's cars can never collide with another's.
+func (s *IngestionServer) writeToRedis(ctx context.Context, tenantID string, carStatus *pb.CarStatus) error {
+	// Check geofence containment before the Redis write so Enter/Exit
+	// alerts fire in the same hot path as ingest, with no extra round trip.
+	s.geofences.Check(tenantID, carStatus.CarId, carStatus.Latitude, carStatus.Longitude)
+
+	key := fmt.Sprintf("fleet:%s:locations", tenantID)
 
 	// GEOADD key longitude latitude member
 	_, err := s.redisClient.GeoAdd(ctx, key, &redis.GeoLocation{
@@ -163,7 +253,7 @@ func (s *IngestionServer) writeToRedis(ctx context.Context, carStatus *pb.CarSta
 	}
 
 	// Also store battery level and timestamp
-	s.redisClient.HSet(ctx, fmt.Sprintf("car:%s", carStatus.CarId), map[string]interface{}{
+	s.redisClient.HSet(ctx, fmt.Sprintf("car:%s:%s", tenantID, carStatus.CarId), map[string]interface{}{
 		"battery":   carStatus.BatteryLevel,
 		"velocity":  carStatus.Velocity,
 		"timestamp": carStatus.Timestamp,
@@ -172,55 +262,186 @@ func (s *IngestionServer) writeToRedis(ctx context.Context, carStatus *pb.CarSta
 	return nil
 }
 
-// emitToKafka sends telemetry event to Redpanda
-func (s *IngestionServer) emitToKafka(ctx context.Context, carStatus *pb.CarStatus) error {
+// emitToKafka sends telemetry event to Redpanda, encoded with whichever
+// codec the server was configured with (JSON or schema-registry Protobuf),
+// and tags the message with the tenant as a Kafka header.
+func (s *IngestionServer) emitToKafka(ctx context.Context, tenantID string, carStatus *pb.CarStatus) error {
+	value, err := s.codec.Encode(carStatus)
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to encode telemetry event: %w", err)
+	}
+
 	message := kafka.Message{
-		Key: []byte(carStatus.CarId),
-		Value: []byte(fmt.Sprintf(`{"car_id":"%s","lat":%.6f,"lon":%.6f,"battery":%.2f,"velocity":%.2f,"timestamp":%d,"event_type":"telemetry"}`,
-			carStatus.CarId,
-			carStatus.Latitude,
-			carStatus.Longitude,
-			carStatus.BatteryLevel,
-			carStatus.Velocity,
-			carStatus.Timestamp,
-		)),
-		Time: time.Now(),
+		Key:   []byte(carStatus.CarId),
+		Value: value,
+		Time:  time.Now(),
+		Headers: []kafka.Header{
+			{Key: "tenant_id", Value: []byte(tenantID)},
+		},
 	}
 
 	return s.kafkaWriter.WriteMessages(ctx, message)
 }
 
-// authUnaryInterceptor validates API token for unary calls
-func authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if err := validateToken(ctx); err != nil {
+// newTelemetryCodec registers the CarStatus schema with the schema
+// registry and returns a Protobuf codec bound to it. If the registry is
+// unreachable, it falls back to the original JSON codec so the service can
+// still start during local development.
+func newTelemetryCodec() codec.TelemetryCodec {
+	registryClient := registry.NewClient(schemaRegistryURL)
+
+	if _, err := registryClient.RegisterProto(telemetrySchemaSubject, carStatusProtoSchema); err != nil {
+		logger.Warnf("failed to register CarStatus schema, falling back to JSON codec: %v", err)
+		return codec.JSONCodec{}
+	}
+
+	logger.Info("Registered CarStatus schema with registry, using Protobuf codec")
+	return codec.ProtobufCodec{
+		Registry: registryClient,
+		Subject:  telemetrySchemaSubject,
+	}
+}
+
+// carStatusProtoSchema is the CarStatus message definition registered
+// against telemetrySchemaSubject; keep in sync with proto/fleet.proto.
+const carStatusProtoSchema = `syntax = "proto3";
+package greenlane.fleet;
+message CarStatus {
+  string car_id = 1;
+  double latitude = 2;
+  double longitude = 3;
+  double battery_level = 4;
+  double velocity = 5;
+  int64 timestamp = 6;
+}`
+
+// authUnaryInterceptor authenticates the caller via s.authn, rate-limits
+// per resolved tenant, and attaches the tenant to the request context.
+func (s *IngestionServer) authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	tenantID, err := s.authn.Authenticate(ctx)
+	if err != nil {
 		return nil, err
 	}
-	return handler(ctx, req)
+	if !s.limiter.Allow(tenantID) {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for tenant %s", tenantID)
+	}
+
+	return handler(auth.WithTenant(ctx, tenantID), req)
 }
 
-// authStreamInterceptor validates API token for streaming calls
-func authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	if err := validateToken(ss.Context()); err != nil {
+// authStreamInterceptor is the streaming counterpart of
+// authUnaryInterceptor. Authentication happens once at stream setup, but
+// rate limiting is enforced per message via tenantServerStream.RecvMsg,
+// since StreamTelemetry fans many records over one call and a per-setup
+// check alone would let an open stream push unlimited messages/sec.
+func (s *IngestionServer) authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	tenantID, err := s.authn.Authenticate(ss.Context())
+	if err != nil {
 		return err
 	}
-	return handler(srv, ss)
+
+	wrapped := &tenantServerStream{
+		ServerStream: ss,
+		ctx:          auth.WithTenant(ss.Context(), tenantID),
+		tenantID:     tenantID,
+		limiter:      s.limiter,
+	}
+	return handler(srv, wrapped)
 }
 
-// validateToken checks for x-api-token in metadata
-func validateToken(ctx context.Context) error {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return status.Error(codes.Unauthenticated, "missing metadata")
+// tenantServerStream overrides Context() so handlers see the tenant
+// attached by the interceptor, since grpc.ServerStream doesn't expose a
+// way to do that directly, and overrides RecvMsg to rate-limit every
+// message the client sends, not just the stream's initial setup.
+type tenantServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	tenantID string
+	limiter  *ratelimit.Limiter
+}
+
+func (s *tenantServerStream) Context() context.Context { return s.ctx }
+
+func (s *tenantServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
 	}
+	if !s.limiter.Allow(s.tenantID) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for tenant %s", s.tenantID)
+	}
+	return nil
+}
 
-	tokens := md.Get("x-api-token")
-	if len(tokens) == 0 {
-		return status.Error(codes.Unauthenticated, "missing x-api-token")
+// newAuthenticator prefers mTLS (the stronger guarantee) when server certs
+// are configured, and otherwise falls back to JWT so the service can still
+// start in environments that haven't provisioned client certs yet.
+func newAuthenticator() auth.Authenticator {
+	if _, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile); err == nil {
+		logger.Info("Using mTLS authentication")
+		return auth.MTLSAuthenticator{}
 	}
 
-	if tokens[0] != apiTokenValue {
-		return status.Error(codes.Unauthenticated, "invalid x-api-token")
+	publicKey, err := loadJWTPublicKey(jwtPublicKeyFile)
+	if err != nil {
+		logger.Fatalf("no mTLS certs and failed to load JWT public key: %v", err)
 	}
 
-	return nil
+	logger.Info("Using JWT authentication")
+	return auth.JWTAuthenticator{
+		PublicKey: publicKey,
+		Issuer:    jwtIssuer,
+		Audience:  jwtAudience,
+	}
+}
+
+// loadServerTLSConfig builds a server TLS config requiring and verifying
+// client certificates, or returns nil if no certs are configured so main
+// can fall back to a plaintext listener for local development.
+func loadServerTLSConfig() *tls.Config {
+	cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+	if err != nil {
+		logger.Warnf("no server TLS certs at %s, starting without mTLS", tlsCertFile)
+		return nil
+	}
+
+	clientCAPEM, err := os.ReadFile(tlsClientCAFile)
+	if err != nil {
+		logger.Fatalf("failed to read client CA file %s: %v", tlsClientCAFile, err)
+	}
+
+	clientCAPool := x509.NewCertPool()
+	if !clientCAPool.AppendCertsFromPEM(clientCAPEM) {
+		logger.Fatalf("failed to parse client CA file %s", tlsClientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAPool,
+	}
+}
+
+// loadJWTPublicKey reads a PEM-encoded RSA public key used to verify JWTs.
+func loadJWTPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT public key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block in %s", path)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key in %s is not an RSA key", path)
+	}
+
+	return rsaPub, nil
 }
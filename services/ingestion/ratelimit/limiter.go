@@ -0,0 +1,63 @@
+// Package ratelimit provides a per-tenant token-bucket rate limiter for
+// the ingestion server's unary and streaming interceptors.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter tracks one token bucket per tenant ID.
+type Limiter struct {
+	ratePerSec float64
+	burst      float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewLimiter returns a Limiter allowing ratePerSec sustained messages per
+// second per tenant, with bursts up to burst tokens.
+func NewLimiter(ratePerSec, burst float64) *Limiter {
+	return &Limiter{
+		ratePerSec: ratePerSec,
+		burst:      burst,
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// Allow reports whether tenant may send one more message right now,
+// consuming a token if so.
+func (l *Limiter) Allow(tenantID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[tenantID]
+	if !ok {
+		b = &bucket{tokens: l.burst, lastFill: now}
+		l.buckets[tenantID] = b
+	}
+
+	elapsed := now.Sub(b.lastFill).Seconds()
+	b.tokens = min(l.burst, b.tokens+elapsed*l.ratePerSec)
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,118 @@
+// Package registry is a minimal Confluent schema-registry client: enough to
+// register the CarStatus proto schema on startup and cache its ID for the
+// lifetime of the process.
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Client talks to a Confluent-compatible schema registry over its REST API
+// and caches schema IDs per subject so hot-path encodes never hit the
+// network.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu    sync.RWMutex
+	cache map[string]int // subject -> schema ID
+}
+
+// NewClient returns a Client pointed at a schema-registry base URL, e.g.
+// "http://localhost:8085".
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{},
+		cache:      make(map[string]int),
+	}
+}
+
+type registerSchemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+type registerSchemaResponse struct {
+	ID int `json:"id"`
+}
+
+// RegisterProto registers a .proto schema definition under subject and
+// caches the resulting ID so later SchemaID calls are free.
+func (c *Client) RegisterProto(subject, protoSchema string) (int, error) {
+	body, err := json.Marshal(registerSchemaRequest{
+		Schema:     protoSchema,
+		SchemaType: "PROTOBUF",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("ERROR: failed to build schema registration request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/subjects/%s/versions", c.baseURL, subject)
+	resp, err := c.httpClient.Post(url, "application/vnd.schemaregistry.v1+json", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("ERROR: failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ERROR: schema registry returned status %d for subject %s", resp.StatusCode, subject)
+	}
+
+	var decoded registerSchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return 0, fmt.Errorf("ERROR: failed to decode schema registry response: %w", err)
+	}
+
+	c.mu.Lock()
+	c.cache[subject] = decoded.ID
+	c.mu.Unlock()
+
+	return decoded.ID, nil
+}
+
+type schemaVersionResponse struct {
+	ID int `json:"id"`
+}
+
+// SchemaID returns the cached schema ID for subject, fetching (and caching)
+// it from the registry on a cache miss. version 0 means "latest".
+func (c *Client) SchemaID(subject string, version int) (int, error) {
+	c.mu.RLock()
+	id, ok := c.cache[subject]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	versionPath := "latest"
+	if version > 0 {
+		versionPath = fmt.Sprintf("%d", version)
+	}
+
+	url := fmt.Sprintf("%s/subjects/%s/versions/%s", c.baseURL, subject, versionPath)
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return 0, fmt.Errorf("ERROR: failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ERROR: schema registry returned status %d for subject %s", resp.StatusCode, subject)
+	}
+
+	var decoded schemaVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return 0, fmt.Errorf("ERROR: failed to decode schema registry response: %w", err)
+	}
+
+	c.mu.Lock()
+	c.cache[subject] = decoded.ID
+	c.mu.Unlock()
+
+	return decoded.ID, nil
+}
@@ -0,0 +1,144 @@
+package grid
+
+import "sync"
+
+// seasonLength is the Holt-Winters period: one full day of hourly samples.
+const seasonLength = 24
+
+const (
+	defaultAlpha = 0.3 // level smoothing
+	defaultBeta  = 0.1 // trend smoothing
+	defaultGamma = 0.3 // seasonal smoothing
+)
+
+// Point is one hour of a forecast.
+type Point struct {
+	Hour        int     `json:"hour"`
+	PricePerKwh float64 `json:"price_per_kwh"`
+}
+
+// Model is a Holt-Winters additive-seasonal forecaster over hourly grid
+// prices (period=24): level Lt = α(yt−St−24)+(1−α)(Lt−1+Tt−1), trend
+// Tt = β(Lt−Lt−1)+(1−β)Tt−1, seasonal St = γ(yt−Lt)+(1−γ)St−24. Since the
+// period is exactly one day, St−24 is just the seasonal component already
+// stored for that hour-of-day, so it's kept as a 24-slot array indexed by
+// hour rather than a sliding window.
+//
+// The first seasonLength observations warm the model up directly from the
+// raw readings (classic Holt-Winters initialization) before the recurrence
+// takes over.
+type Model struct {
+	alpha, beta, gamma float64
+
+	mu       sync.Mutex
+	warmup   []Point
+	ready    bool
+	level    float64
+	trend    float64
+	seasonal [seasonLength]float64
+}
+
+// NewModel returns a Model using the default smoothing constants
+// (α=0.3, β=0.1, γ=0.3).
+func NewModel() *Model {
+	return &Model{alpha: defaultAlpha, beta: defaultBeta, gamma: defaultGamma}
+}
+
+// Observe feeds a new hourly reading into the model.
+func (m *Model) Observe(hour int, price float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.ready {
+		m.warmup = append(m.warmup, Point{Hour: hour, PricePerKwh: price})
+		if len(m.warmup) >= seasonLength {
+			m.seedFromWarmup()
+		}
+		return
+	}
+
+	prevLevel := m.level
+	level := m.alpha*(price-m.seasonal[hour]) + (1-m.alpha)*(m.level+m.trend)
+	m.trend = m.beta*(level-prevLevel) + (1-m.beta)*m.trend
+	m.seasonal[hour] = m.gamma*(price-level) + (1-m.gamma)*m.seasonal[hour]
+	m.level = level
+}
+
+// seedFromWarmup initializes level as the mean of the warm-up window and
+// each seasonal slot as that hour's deviation from the mean. Must be
+// called with mu held.
+func (m *Model) seedFromWarmup() {
+	var sum float64
+	for _, p := range m.warmup {
+		sum += p.PricePerKwh
+	}
+	m.level = sum / float64(len(m.warmup))
+	for _, p := range m.warmup {
+		m.seasonal[p.Hour] = p.PricePerKwh - m.level
+	}
+	m.trend = 0
+	m.ready = true
+	m.warmup = nil
+}
+
+// Seed initializes the model directly from a restored History snapshot
+// (see History.Snapshot), skipping the warm-up that would otherwise take
+// seasonLength fresh Observe calls — without this, a restart would throw
+// away up to a day of history and return 503s from Forecast in the
+// meantime. Hours with no recorded price (seen[hour] == false) are left
+// at a 0 seasonal deviation. A no-op once the model is already seeded or
+// warmed up, and a no-op if the snapshot has no data at all.
+func (m *Model) Seed(prices [seasonLength]float64, seen [seasonLength]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ready {
+		return
+	}
+
+	var sum float64
+	var count int
+	for hour, ok := range seen {
+		if ok {
+			sum += prices[hour]
+			count++
+		}
+	}
+	if count == 0 {
+		return
+	}
+
+	m.level = sum / float64(count)
+	for hour, ok := range seen {
+		if ok {
+			m.seasonal[hour] = prices[hour] - m.level
+		}
+	}
+	m.trend = 0
+	m.ready = true
+	m.warmup = nil
+}
+
+// Ready reports whether the model has absorbed a full season and is
+// producing forecasts off the recurrence rather than raw warm-up data.
+func (m *Model) Ready() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ready
+}
+
+// Forecast projects prices for the horizonHours following lastHour, per
+// ŷt+h = Lt + h·Tt + St−24+((h−1) mod 24)+1 — the seasonal term for the
+// hour-of-day that h lands on.
+func (m *Model) Forecast(lastHour, horizonHours int) []Point {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	points := make([]Point, 0, horizonHours)
+	for h := 1; h <= horizonHours; h++ {
+		hour := (lastHour + h) % seasonLength
+		price := m.level + float64(h)*m.trend + m.seasonal[hour]
+		points = append(points, Point{Hour: hour, PricePerKwh: price})
+	}
+	return points
+}
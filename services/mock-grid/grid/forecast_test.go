@@ -0,0 +1,118 @@
+package grid
+
+import "testing"
+
+const eps = 1e-9
+
+func TestModel_ObserveWarmup(t *testing.T) {
+	m := NewModel()
+
+	for hour := 0; hour < seasonLength-1; hour++ {
+		m.Observe(hour, 0.30)
+		if m.Ready() {
+			t.Fatalf("Ready() = true after %d observations, want false until %d", hour+1, seasonLength)
+		}
+	}
+
+	m.Observe(seasonLength-1, 0.30)
+	if !m.Ready() {
+		t.Fatalf("Ready() = false after a full season of observations, want true")
+	}
+}
+
+func TestModel_ForecastConstantPrice(t *testing.T) {
+	m := NewModel()
+	for hour := 0; hour < seasonLength; hour++ {
+		m.Observe(hour, 0.30)
+	}
+
+	points := m.Forecast(0, 6)
+	if len(points) != 6 {
+		t.Fatalf("len(points) = %d, want 6", len(points))
+	}
+	for _, p := range points {
+		if diff := p.PricePerKwh - 0.30; diff > eps || diff < -eps {
+			t.Errorf("hour %d: price = %v, want ~0.30", p.Hour, p.PricePerKwh)
+		}
+	}
+}
+
+func TestModel_Seed(t *testing.T) {
+	var prices [seasonLength]float64
+	var seen [seasonLength]bool
+	for hour := range prices {
+		prices[hour] = 0.20
+		seen[hour] = true
+	}
+	prices[18] = 0.40 // evening peak
+
+	m := NewModel()
+	m.Seed(prices, seen)
+
+	if !m.Ready() {
+		t.Fatalf("Ready() = false after Seed with a full snapshot, want true")
+	}
+
+	points := m.Forecast(17, 1) // next hour after 17 is 18
+	if len(points) != 1 || points[0].Hour != 18 {
+		t.Fatalf("Forecast(17, 1) = %+v, want a single point for hour 18", points)
+	}
+	if diff := points[0].PricePerKwh - 0.40; diff > eps || diff < -eps {
+		t.Errorf("forecast for hour 18 = %v, want ~0.40", points[0].PricePerKwh)
+	}
+}
+
+func TestModel_SeedNoopOnceReady(t *testing.T) {
+	m := NewModel()
+	for hour := 0; hour < seasonLength; hour++ {
+		m.Observe(hour, 0.30)
+	}
+
+	var prices [seasonLength]float64
+	var seen [seasonLength]bool
+	for hour := range prices {
+		prices[hour] = 0.99
+		seen[hour] = true
+	}
+	m.Seed(prices, seen)
+
+	points := m.Forecast(0, 1)
+	if diff := points[0].PricePerKwh - 0.30; diff > eps || diff < -eps {
+		t.Errorf("Seed overwrote an already-warmed-up model: forecast = %v, want ~0.30", points[0].PricePerKwh)
+	}
+}
+
+func TestModel_SeedNoopOnEmptySnapshot(t *testing.T) {
+	m := NewModel()
+	var prices [seasonLength]float64
+	var seen [seasonLength]bool // nothing recorded
+
+	m.Seed(prices, seen)
+	if m.Ready() {
+		t.Fatalf("Ready() = true after Seed with an empty snapshot, want false")
+	}
+}
+
+func TestModel_SeedPartialSnapshot(t *testing.T) {
+	var prices [seasonLength]float64
+	var seen [seasonLength]bool
+	prices[5] = 0.10
+	seen[5] = true
+	prices[17] = 0.30
+	seen[17] = true
+
+	m := NewModel()
+	m.Seed(prices, seen)
+
+	if !m.Ready() {
+		t.Fatalf("Ready() = false after Seed with a partial snapshot, want true")
+	}
+
+	// level is the mean of the two recorded hours; hour 12 was never seen
+	// so its seasonal deviation stays at 0.
+	wantLevel := 0.20
+	points := m.Forecast(11, 1)
+	if diff := points[0].PricePerKwh - wantLevel; diff > eps || diff < -eps {
+		t.Errorf("forecast for an unseen hour = %v, want ~%v (level, zero deviation)", points[0].PricePerKwh, wantLevel)
+	}
+}
@@ -0,0 +1,87 @@
+package grid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// historyRedisKey is the Redis hash mirroring History's in-memory rolling
+// window, keyed by hour-of-day ("0".."23") so a restart can warm back up
+// from the last price seen at each hour.
+const historyRedisKey = "grid:history"
+
+// History keeps a rolling 24h time series of hourly prices, one sample per
+// hour-of-day, mirrored to Redis so it survives a restart.
+type History struct {
+	redisClient *redis.Client
+
+	mu     sync.RWMutex
+	byHour [24]float64
+	seen   [24]bool
+}
+
+// NewHistory returns a History backed by redisClient, restoring whatever
+// hourly prices are already in grid:history. A non-nil error means the
+// restore failed (e.g. Redis unreachable); the returned History is still
+// usable, just empty.
+func NewHistory(ctx context.Context, redisClient *redis.Client) (*History, error) {
+	h := &History{redisClient: redisClient}
+	if err := h.restore(ctx); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+func (h *History) restore(ctx context.Context) error {
+	if h.redisClient == nil {
+		return nil
+	}
+
+	values, err := h.redisClient.HGetAll(ctx, historyRedisKey).Result()
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to restore grid history from Redis: %w", err)
+	}
+
+	for hourStr, priceStr := range values {
+		hour, err := strconv.Atoi(hourStr)
+		if err != nil || hour < 0 || hour >= 24 {
+			continue
+		}
+		price, err := strconv.ParseFloat(priceStr, 64)
+		if err != nil {
+			continue
+		}
+		h.byHour[hour] = price
+		h.seen[hour] = true
+	}
+	return nil
+}
+
+// Record stores price as the latest reading for hour, both in memory and
+// in Redis.
+func (h *History) Record(ctx context.Context, hour int, price float64) error {
+	h.mu.Lock()
+	h.byHour[hour] = price
+	h.seen[hour] = true
+	h.mu.Unlock()
+
+	if h.redisClient == nil {
+		return nil
+	}
+	if err := h.redisClient.HSet(ctx, historyRedisKey, hour, price).Err(); err != nil {
+		return fmt.Errorf("ERROR: failed to persist grid history to Redis: %w", err)
+	}
+	return nil
+}
+
+// Snapshot returns the 24 most recent hourly prices indexed by hour-of-day,
+// and which of them have ever been recorded.
+func (h *History) Snapshot() (prices [24]float64, seen [24]bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.byHour, h.seen
+}
@@ -0,0 +1,170 @@
+// Package grid supplies the mock grid service's pricing data: a pluggable
+// PriceSource (the original sinusoid, or a polled upstream feed), a
+// rolling 24h history of what each source has reported, and a Holt-Winters
+// forecaster built on top of that history.
+package grid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Reading is one sampled price point, in the shape the pricing API has
+// always returned.
+type Reading struct {
+	Timestamp    int64   `json:"timestamp"`
+	PricePerKwh  float64 `json:"price_per_kwh"`
+	GridLoad     string  `json:"grid_load"`
+	EnergySource string  `json:"energy_source"`
+	Hour         int     `json:"hour"`
+}
+
+// PriceSource produces the current grid price reading. SinusoidSource is
+// the original deterministic generator; RESTSource polls a live upstream
+// feed and falls back to another PriceSource when that feed is down.
+type PriceSource interface {
+	Reading(ctx context.Context, now time.Time) (Reading, error)
+}
+
+// SinusoidSource is the original deterministic generator: peak at noon,
+// trough at midnight, with a solar discount during daylight hours.
+type SinusoidSource struct{}
+
+const (
+	basePricePerKwh = 0.25 // $0.25 base price
+	amplitude       = 0.15 // $0.15 swing
+)
+
+func (SinusoidSource) Reading(_ context.Context, now time.Time) (Reading, error) {
+	hour := now.Hour()
+
+	// Phase shift: sin wave peaks at π/2, and shifting the hour by -6
+	// puts that peak at hour 12 (noon) and the trough at hour 0.
+	radians := (float64(hour) - 6) * math.Pi / 12
+	pricePerKwh := basePricePerKwh + amplitude*math.Sin(radians)
+
+	var gridLoad string
+	switch {
+	case pricePerKwh > 0.35:
+		gridLoad = "High"
+	case pricePerKwh > 0.25:
+		gridLoad = "Medium"
+	default:
+		gridLoad = "Low"
+	}
+
+	// Solar during the day, wind in the evening, grid overnight.
+	var energySource string
+	switch {
+	case hour >= 8 && hour <= 18:
+		energySource = "Solar"
+	case hour >= 19 && hour <= 22:
+		energySource = "Wind"
+	default:
+		energySource = "Grid"
+	}
+
+	if energySource == "Solar" {
+		pricePerKwh *= 0.9
+	}
+
+	return Reading{
+		Timestamp:    now.UnixMilli(),
+		PricePerKwh:  math.Round(pricePerKwh*100) / 100,
+		GridLoad:     gridLoad,
+		EnergySource: energySource,
+		Hour:         hour,
+	}, nil
+}
+
+const (
+	restInitialBackoff = 100 * time.Millisecond
+	restMaxBackoff     = 5 * time.Second
+	restMaxAttempts    = 3
+)
+
+// RESTSource polls an upstream REST pricing feed (any URL returning a JSON
+// body shaped like Reading) with exponential backoff and jitter, falling
+// back to Fallback once every attempt in a poll has failed.
+type RESTSource struct {
+	URL        string
+	HTTPClient *http.Client
+	Fallback   PriceSource
+}
+
+// NewRESTSource returns a RESTSource polling url, falling back to fallback
+// (typically a SinusoidSource) when the upstream feed can't be reached.
+func NewRESTSource(url string, fallback PriceSource) *RESTSource {
+	return &RESTSource{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		Fallback:   fallback,
+	}
+}
+
+func (s *RESTSource) Reading(ctx context.Context, now time.Time) (Reading, error) {
+	backoff := restInitialBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= restMaxAttempts; attempt++ {
+		reading, err := s.fetch(ctx)
+		if err == nil {
+			return reading, nil
+		}
+		lastErr = err
+
+		if attempt == restMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return Reading{}, ctx.Err()
+		case <-time.After(withJitter(backoff)):
+		}
+		backoff *= 2
+		if backoff > restMaxBackoff {
+			backoff = restMaxBackoff
+		}
+	}
+
+	if s.Fallback != nil {
+		return s.Fallback.Reading(ctx, now)
+	}
+	return Reading{}, fmt.Errorf("ERROR: upstream price feed unreachable: %w", lastErr)
+}
+
+func (s *RESTSource) fetch(ctx context.Context) (Reading, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return Reading{}, fmt.Errorf("ERROR: failed to build upstream price request: %w", err)
+	}
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return Reading{}, fmt.Errorf("ERROR: failed to reach upstream price feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Reading{}, fmt.Errorf("ERROR: upstream price feed returned status %d", resp.StatusCode)
+	}
+
+	var reading Reading
+	if err := json.NewDecoder(resp.Body).Decode(&reading); err != nil {
+		return Reading{}, fmt.Errorf("ERROR: failed to decode upstream price feed response: %w", err)
+	}
+	return reading, nil
+}
+
+// withJitter returns a duration in [d/2, d], mirroring the ingestion
+// retry queue's backoff so repeated polls don't synchronize.
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
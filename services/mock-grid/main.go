@@ -1,95 +1,201 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"math"
 	"net/http"
+	"os"
 	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/greenlane/mock-grid/grid"
+	"github.com/greenlane/observability/logging"
+	"github.com/greenlane/observability/metrics"
 )
 
 const (
-	httpPort = ":8081"
+	httpPort    = ":8081"
+	metricsAddr = ":9091"
+
+	redisAddr       = "localhost:6379"
+	kafkaBroker     = "localhost:19092"
+	gridPricesTopic = "grid-prices"
+
+	defaultForecastHorizon = 6 * time.Hour
+
+	// upstreamPriceURLEnvVar, when set, switches priceSource from the
+	// built-in sinusoid to a RESTSource polling that URL.
+	upstreamPriceURLEnvVar = "GREENLANE_GRID_UPSTREAM_URL"
+)
+
+var logger = logging.New("mock-grid")
+
+var (
+	pricingRequestsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mockgrid_pricing_requests_total",
+		Help: "Requests served by /api/pricing.",
+	})
+	forecastRequestsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mockgrid_forecast_requests_total",
+		Help: "Requests served by /api/pricing/forecast.",
+	})
 )
 
-// PriceResponse represents the current energy pricing
-type PriceResponse struct {
-	Timestamp    int64   `json:"timestamp"`
-	PricePerKwh  float64 `json:"price_per_kwh"`
-	GridLoad     string  `json:"grid_load"`      // "Low", "Medium", "High"
-	EnergySource string  `json:"energy_source"`  // "Solar", "Wind", "Grid"
-	Hour         int     `json:"hour"`
+func init() {
+	prometheus.MustRegister(pricingRequestsTotal, forecastRequestsTotal)
 }
 
+var (
+	priceSource   grid.PriceSource
+	priceHistory  *grid.History
+	forecastModel = grid.NewModel()
+	kafkaWriter   *kafka.Writer
+)
+
 func main() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisAddr,
+		Password: "",
+		DB:       0,
+	})
+
+	ctx := context.Background()
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		logger.Warnf("Failed to connect to Redis, grid history will not persist: %v", err)
+	} else {
+		logger.Info("Connected to Redis")
+	}
+
+	history, err := grid.NewHistory(ctx, redisClient)
+	if err != nil {
+		logger.Warnf("Failed to restore grid history: %v", err)
+	}
+	priceHistory = history
+
+	prices, seen := priceHistory.Snapshot()
+	forecastModel.Seed(prices, seen)
+	if forecastModel.Ready() {
+		logger.Info("Seeded forecast model from restored grid history")
+	}
+
+	priceSource = grid.SinusoidSource{}
+	if upstreamURL := os.Getenv(upstreamPriceURLEnvVar); upstreamURL != "" {
+		priceSource = grid.NewRESTSource(upstreamURL, grid.SinusoidSource{})
+		logger.Infof("Polling upstream price feed at %s", upstreamURL)
+	} else {
+		logger.Info("Serving sinusoidal pricing data (high at noon, low at midnight)")
+	}
+
+	kafkaWriter = &kafka.Writer{
+		Addr:                   kafka.TCP(kafkaBroker),
+		Topic:                  gridPricesTopic,
+		Balancer:               &kafka.LeastBytes{},
+		AllowAutoTopicCreation: true,
+	}
+	defer kafkaWriter.Close()
+
 	http.HandleFunc("/api/pricing", handlePricing)
+	http.HandleFunc("/api/pricing/forecast", handleForecast)
 	http.HandleFunc("/health", handleHealth)
 
-	log.Printf("🌞 Mock Grid Service started on %s", httpPort)
-	log.Println("📊 Serving sinusoidal pricing data (high at 6pm, low at 2am)")
-	
+	metrics.ServeSidecar(metricsAddr, nil)
+
+	logger.Infof("Mock Grid Service started on %s", httpPort)
+
 	if err := http.ListenAndServe(httpPort, nil); err != nil {
-		log.Fatalf("❌ Server failed: %v", err)
+		logger.Fatalf("Server failed: %v", err)
 	}
 }
 
-// handlePricing returns dynamic pricing based on time of day
+// handlePricing returns the current reading from priceSource, feeding it
+// into the rolling history and the forecast model along the way.
 func handlePricing(w http.ResponseWriter, r *http.Request) {
-	now := time.Now()
-	hour := now.Hour()
-	
-	// Calculate price using sinusoidal function
-	// Peak at 6pm (18:00), lowest at 2am (2:00)
-	// Price formula: base + amplitude * sin(phase_shift)
-	basePricePerKwh := 0.25  // $0.25 base price
-	amplitude := 0.15         // $0.15 swing
-	
-	// Phase shift: peak at hour 18 (6pm)
-	// sin wave: peaks at π/2, so we shift the hour by -6 to center peak at 18
-	hoursFromMidnight := float64(hour)
-	radians := (hoursFromMidnight - 6) * math.Pi / 12  // Convert to radians, shift peak
-	
-	pricePerKwh := basePricePerKwh + amplitude * math.Sin(radians)
-	
-	// Determine grid load based on price
-	var gridLoad string
-	if pricePerKwh > 0.35 {
-		gridLoad = "High"
-	} else if pricePerKwh > 0.25 {
-		gridLoad = "Medium"
-	} else {
-		gridLoad = "Low"
+	pricingRequestsTotal.Inc()
+
+	ctx := r.Context()
+	reading, err := priceSource.Reading(ctx, time.Now())
+	if err != nil {
+		logger.Errorf("Failed to get price reading: %v", err)
+		http.Error(w, "failed to read grid price", http.StatusBadGateway)
+		return
 	}
-	
-	// Determine energy source (solar during day, grid at night)
-	var energySource string
-	if hour >= 8 && hour <= 18 {
-		energySource = "Solar"
-	} else if hour >= 19 && hour <= 22 {
-		energySource = "Wind"
-	} else {
-		energySource = "Grid"
+
+	forecastModel.Observe(reading.Hour, reading.PricePerKwh)
+	if priceHistory != nil {
+		if err := priceHistory.Record(ctx, reading.Hour, reading.PricePerKwh); err != nil {
+			logger.Warnf("Failed to record grid history: %v", err)
+		}
+	}
+
+	logger.Infof("[%02d:00] Price: $%.3f/kWh | Load: %s | Source: %s",
+		reading.Hour, reading.PricePerKwh, reading.GridLoad, reading.EnergySource)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reading)
+}
+
+// handleForecast returns an hour-by-hour price projection for ?horizon=
+// (e.g. "6h"), defaulting to defaultForecastHorizon, and publishes the
+// same projection to grid-prices.
+func handleForecast(w http.ResponseWriter, r *http.Request) {
+	forecastRequestsTotal.Inc()
+
+	horizon := defaultForecastHorizon
+	if raw := r.URL.Query().Get("horizon"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			http.Error(w, `invalid horizon, expected a duration like "6h"`, http.StatusBadRequest)
+			return
+		}
+		horizon = parsed
 	}
-	
-	// If solar, reduce price slightly
-	if energySource == "Solar" {
-		pricePerKwh *= 0.9
+
+	horizonHours := int(horizon.Hours())
+	if horizonHours <= 0 {
+		http.Error(w, "horizon must be at least 1h", http.StatusBadRequest)
+		return
+	}
+
+	if !forecastModel.Ready() {
+		http.Error(w, "forecast model is still warming up, needs 24h of samples", http.StatusServiceUnavailable)
+		return
 	}
-	
-	response := PriceResponse{
-		Timestamp:    now.UnixMilli(),
-		PricePerKwh:  math.Round(pricePerKwh*100) / 100,  // Round to 2 decimals
-		GridLoad:     gridLoad,
-		EnergySource: energySource,
-		Hour:         hour,
+
+	points := forecastModel.Forecast(time.Now().Hour(), horizonHours)
+
+	if err := publishForecast(r.Context(), points); err != nil {
+		logger.Warnf("Failed to publish forecast to %s: %v", gridPricesTopic, err)
 	}
-	
-	log.Printf("💰 [%02d:00] Price: $%.3f/kWh | Load: %s | Source: %s",
-		hour, response.PricePerKwh, gridLoad, energySource)
-	
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(points)
+}
+
+// forecastUpdate is what lands on grid-prices each time /forecast is hit.
+type forecastUpdate struct {
+	GeneratedAt int64        `json:"generated_at"`
+	Points      []grid.Point `json:"points"`
+}
+
+// publishForecast emits the freshly computed forecast to grid-prices so
+// the ingestion path can factor predicted cost into routing decisions.
+func publishForecast(ctx context.Context, points []grid.Point) error {
+	value, err := json.Marshal(forecastUpdate{
+		GeneratedAt: time.Now().UnixMilli(),
+		Points:      points,
+	})
+	if err != nil {
+		return fmt.Errorf("ERROR: failed to marshal forecast: %w", err)
+	}
+
+	return kafkaWriter.WriteMessages(ctx, kafka.Message{
+		Value: value,
+		Time:  time.Now(),
+	})
 }
 
 // handleHealth returns service health status